@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// GuaranteedShareAnnotation names the per-resource guaranteed share a
+// namespace is entitled to, expressed as a ResourceQuota annotation because
+// ResourceQuota's Spec.Hard already caps usage but has no notion of a
+// floor shared fairly among tenants on the same node.
+const GuaranteedShareAnnotation = "scheduling.k8s.io/guaranteed"
+
+// PreemptForFairShare is the preemption reason recorded on events when a
+// namespace's pods are preempted not for priority but to restore another
+// namespace to its guaranteed share of a resource.
+const PreemptForFairShare = "PreemptForFairShare"
+
+// NamespaceShare describes one namespace's entitlement to, and current
+// consumption of, a resource on a node shared by multiple tenants.
+type NamespaceShare struct {
+	Namespace string
+	// Guaranteed is the namespace's guaranteed share, from the
+	// GuaranteedShareAnnotation on its ResourceQuota.
+	Guaranteed int64
+	// Used is the namespace's current usage of the resource, from the
+	// ResourceQuota's Status.Used.
+	Used int64
+}
+
+// overShare returns how far a namespace is over its guaranteed share; zero
+// or negative means it is at or under its entitlement.
+func (s NamespaceShare) overShare() int64 {
+	return s.Used - s.Guaranteed
+}
+
+// OverShareFraction returns how much of a pod's resource request sits
+// inside its namespace's current overshoot, as a fraction in [0, 1]. It is
+// the per-namespace "over-share" signal folded into preemption candidate
+// scoring: a pod fully inside the overshoot scores 1 (fully preferred for
+// preemption on fairness grounds), a pod fully inside the guarantee scores
+// 0 (never preferred on fairness grounds alone), and a pod straddling the
+// boundary scores proportionally.
+func OverShareFraction(share NamespaceShare, podRequest int64) float64 {
+	over := share.overShare()
+	if over <= 0 || podRequest <= 0 {
+		return 0
+	}
+	if podRequest <= over {
+		return 1
+	}
+	return float64(over) / float64(podRequest)
+}
+
+// victimFairShareRank pairs a pod with the over-share fraction its removal
+// would address.
+type victimFairShareRank struct {
+	pod      *v1.Pod
+	fraction float64
+}
+
+// SelectFairShareVictims ranks candidates, all equal-or-higher priority
+// than the preemptor, by how much of their namespace's over-share they sit
+// inside, highest first, and returns the prefix of that ordering whose
+// combined podRequest reaches required without exceeding the namespace's
+// overshoot by more than one pod's worth. It stops selecting once shares
+// are balanced: a namespace's pods stop being fairness-preferred victims
+// once removing them would bring that namespace back to (at most) its
+// guaranteed share, even though the remaining candidates may have equal or
+// slightly higher priority than the pods they would otherwise protect.
+//
+// SelectFairShareVictims and OverShareFraction are standalone building
+// blocks: nothing in this tree yet calls them from the real victim
+// selection path, so preemption does not actually enforce per-namespace
+// fair share for any cluster until one is wired in.
+func SelectFairShareVictims(candidates []*v1.Pod, shares map[string]NamespaceShare, requestOf func(*v1.Pod) int64, required int64) []*v1.Pod {
+	ranked := make([]victimFairShareRank, 0, len(candidates))
+	for _, pod := range candidates {
+		share, ok := shares[pod.Namespace]
+		if !ok {
+			continue
+		}
+		fraction := OverShareFraction(share, requestOf(pod))
+		if fraction <= 0 {
+			continue
+		}
+		ranked = append(ranked, victimFairShareRank{pod: pod, fraction: fraction})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].fraction != ranked[j].fraction {
+			return ranked[i].fraction > ranked[j].fraction
+		}
+		return requestOf(ranked[i].pod) > requestOf(ranked[j].pod)
+	})
+
+	var freed int64
+	remaining := make(map[string]int64, len(shares))
+	for ns, share := range shares {
+		remaining[ns] = share.overShare()
+	}
+	selected := make([]*v1.Pod, 0, len(ranked))
+	for _, r := range ranked {
+		if freed >= required {
+			break
+		}
+		if remaining[r.pod.Namespace] <= 0 {
+			// This namespace is already back at its guaranteed share.
+			continue
+		}
+		selected = append(selected, r.pod)
+		freed += requestOf(r.pod)
+		remaining[r.pod.Namespace] -= requestOf(r.pod)
+	}
+	if freed < required {
+		return nil
+	}
+	return selected
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func namespacedPod(name, namespace string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+}
+
+func TestOverShareFraction(t *testing.T) {
+	atGuarantee := NamespaceShare{Namespace: "a", Guaranteed: 100, Used: 100}
+	if f := OverShareFraction(atGuarantee, 10); f != 0 {
+		t.Errorf("expected 0 over-share fraction at guarantee, got %v", f)
+	}
+
+	over := NamespaceShare{Namespace: "a", Guaranteed: 100, Used: 130}
+	if f := OverShareFraction(over, 10); f != 1 {
+		t.Errorf("expected a pod entirely inside the overshoot to score 1, got %v", f)
+	}
+	if f := OverShareFraction(over, 60); f < 0.49 || f > 0.51 {
+		t.Errorf("expected a pod straddling the overshoot boundary to score proportionally (~0.5), got %v", f)
+	}
+}
+
+func TestSelectFairShareVictimsStopsOnceBalanced(t *testing.T) {
+	shares := map[string]NamespaceShare{
+		"tenant-a": {Namespace: "tenant-a", Guaranteed: 100, Used: 160},
+	}
+	requestOf := func(pod *v1.Pod) int64 { return 20 }
+
+	candidates := []*v1.Pod{
+		namespacedPod("a1", "tenant-a"),
+		namespacedPod("a2", "tenant-a"),
+		namespacedPod("a3", "tenant-a"),
+		namespacedPod("a4", "tenant-a"),
+	}
+
+	selected := SelectFairShareVictims(candidates, shares, requestOf, 60)
+	if len(selected) != 3 {
+		t.Fatalf("expected exactly 3 pods (60 of 60 required freed) to be selected, got %d", len(selected))
+	}
+}
+
+func TestSelectFairShareVictimsIgnoresPodsWithinGuarantee(t *testing.T) {
+	shares := map[string]NamespaceShare{
+		"tenant-b": {Namespace: "tenant-b", Guaranteed: 100, Used: 80},
+	}
+	requestOf := func(pod *v1.Pod) int64 { return 20 }
+
+	candidates := []*v1.Pod{namespacedPod("b1", "tenant-b")}
+	selected := SelectFairShareVictims(candidates, shares, requestOf, 10)
+	if selected != nil {
+		t.Fatalf("expected no victims when the namespace is within its guarantee, got %v", selected)
+	}
+}
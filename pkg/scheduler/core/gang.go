@@ -0,0 +1,203 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+)
+
+const (
+	// PodGroupAnnotationKey names the gang a pod belongs to. Pods that share
+	// the same name under this annotation, in the same namespace, are
+	// scheduled atomically: either at least MinMember of them can be bound
+	// within a single bounded scheduling cycle, or none of them are.
+	PodGroupAnnotationKey = "scheduling.k8s.io/pod-group"
+	// PodGroupMinMemberAnnotationKey is the minimum number of pods of the
+	// named group that must be schedulable together before any of them
+	// binds. Defaults to 1 (no gang behavior) if omitted or invalid.
+	PodGroupMinMemberAnnotationKey = "scheduling.k8s.io/min-member"
+)
+
+// PodGroup is a lightweight, CRD-like stand-in for users who would rather
+// declare a gang out-of-band than annotate every pod. It is keyed by
+// Namespace/Name. PodGang and GangCache read only the pod annotations;
+// nothing in this package consults a PodGroup.
+type PodGroup struct {
+	Namespace string
+	Name      string
+	// MinMember is the minimum number of pods of the group that must be
+	// scheduled together for any of them to bind.
+	MinMember int32
+}
+
+// gangKey identifies a pod group within a namespace.
+type gangKey struct {
+	namespace string
+	name      string
+}
+
+// gangAttempt tracks one in-flight, bounded scheduling cycle for a gang.
+type gangAttempt struct {
+	minMember int32
+	// bound holds pods this gang has tentatively assumed a node for during
+	// the current cycle, keyed by pod UID and valued by node name.
+	bound map[types.UID]string
+	// started marks when the first member of this attempt was assumed; the
+	// attempt is rolled back if it does not reach minMember members bound
+	// within GangCache.cycleTimeout of this time.
+	started time.Time
+}
+
+// GangCache coordinates all-or-none scheduling of gang-scheduled pods.
+// A pod may only bind once every other pod needed to reach MinMember for its
+// gang has also been assumed in the same cycle; if the cycle times out
+// before that happens, every pod assumed so far for that gang is rolled
+// back so none of them hold a node half-scheduled.
+//
+// This is a standalone building block: nothing in this tree yet calls
+// AssumePending/SelectGangVictims from a real binding or preemption call
+// site, so gang scheduling is not actually enforced for any cluster until
+// one is wired in.
+type GangCache struct {
+	mu           sync.Mutex
+	attempts     map[gangKey]*gangAttempt
+	cycleTimeout time.Duration
+}
+
+// NewGangCache returns a GangCache that abandons an incomplete gang attempt
+// after cycleTimeout has elapsed since its first member was assumed.
+func NewGangCache(cycleTimeout time.Duration) *GangCache {
+	return &GangCache{
+		attempts:     make(map[gangKey]*gangAttempt),
+		cycleTimeout: cycleTimeout,
+	}
+}
+
+// PodGang returns the gang name and MinMember a pod declared via
+// annotations, and whether it declared one at all. An invalid or missing
+// min-member value defaults to 1, i.e. no gang behavior beyond the name.
+func PodGang(pod *v1.Pod) (name string, minMember int32, ok bool) {
+	name, ok = pod.Annotations[PodGroupAnnotationKey]
+	if !ok || name == "" {
+		return "", 0, false
+	}
+	minStr, hasMin := pod.Annotations[PodGroupMinMemberAnnotationKey]
+	if !hasMin {
+		return name, 1, true
+	}
+	var parsed int32
+	if _, err := fmt.Sscanf(minStr, "%d", &parsed); err != nil || parsed < 1 {
+		return name, 1, true
+	}
+	return name, parsed, true
+}
+
+// AssumePending records that pod has tentatively been assigned nodeName as
+// part of its gang's current scheduling cycle. It returns whether the gang
+// has now reached MinMember bound pods and may be bound for real, along with
+// the full set of pods assumed so far for that gang.
+func (c *GangCache) AssumePending(pod *v1.Pod, nodeName string) (ready bool, assumed map[types.UID]string) {
+	name, minMember, ok := PodGang(pod)
+	if !ok {
+		// Not a gang pod: it is its own trivially-complete gang of one.
+		return true, map[types.UID]string{pod.UID: nodeName}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := gangKey{namespace: pod.Namespace, name: name}
+	attempt, exists := c.attempts[key]
+	if !exists || time.Since(attempt.started) > c.cycleTimeout {
+		if exists {
+			klog.V(3).Infof("gang %s/%s: previous attempt timed out after %v, starting a new cycle", pod.Namespace, name, c.cycleTimeout)
+		}
+		attempt = &gangAttempt{
+			minMember: minMember,
+			bound:     make(map[types.UID]string),
+			started:   time.Now(),
+		}
+		c.attempts[key] = attempt
+	}
+
+	attempt.bound[pod.UID] = nodeName
+
+	assumed = make(map[types.UID]string, len(attempt.bound))
+	for uid, node := range attempt.bound {
+		assumed[uid] = node
+	}
+	return int32(len(attempt.bound)) >= attempt.minMember, assumed
+}
+
+// Abandon rolls back an incomplete gang attempt, e.g. after its cycle timed
+// out without reaching MinMember, so none of its tentatively-bound pods are
+// left holding a node. It returns the set of pods that were rolled back.
+func (c *GangCache) Abandon(namespace, name string) map[types.UID]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := gangKey{namespace: namespace, name: name}
+	attempt, ok := c.attempts[key]
+	if !ok {
+		return nil
+	}
+	delete(c.attempts, key)
+	return attempt.bound
+}
+
+// Complete marks a gang's cycle as finished (successfully or not), clearing
+// it so the next pod for that gang starts a fresh cycle.
+func (c *GangCache) Complete(namespace, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.attempts, gangKey{namespace: namespace, name: name})
+}
+
+// NodeVictims describes the pods that would need to be removed from a node
+// to free enough room for one gang member to bind there.
+type NodeVictims struct {
+	NodeName string
+	Victims  []*v1.Pod
+}
+
+// SelectGangVictims picks one NodeVictims per gang member from candidates,
+// assigning each member a distinct node, so the whole gang is preempted-for
+// as a single unit rather than one member at a time. Among candidate sets it
+// prefers the ones with the fewest victims, to minimize total disruption.
+// It returns nil if gang cannot be placed as a whole, e.g. because fewer
+// candidate nodes exist than gang members; callers must not preempt any
+// victim in that case, since doing so would disrupt pods for no benefit.
+func SelectGangVictims(gang []*v1.Pod, candidates []NodeVictims) []NodeVictims {
+	if len(candidates) < len(gang) {
+		return nil
+	}
+
+	sorted := make([]NodeVictims, len(candidates))
+	copy(sorted, candidates)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i].Victims) < len(sorted[j].Victims)
+	})
+
+	return sorted[:len(gang)]
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func gangPod(uid, namespace, group, minMember string) *v1.Pod {
+	annotations := map[string]string{}
+	if group != "" {
+		annotations[PodGroupAnnotationKey] = group
+	}
+	if minMember != "" {
+		annotations[PodGroupMinMemberAnnotationKey] = minMember
+	}
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:         types.UID(uid),
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+	}
+}
+
+func TestPodGang(t *testing.T) {
+	cases := []struct {
+		name          string
+		pod           *v1.Pod
+		wantGang      string
+		wantMinMember int32
+		wantOK        bool
+	}{
+		{"no annotation", gangPod("1", "ns", "", ""), "", 0, false},
+		{"group only", gangPod("1", "ns", "g1", ""), "g1", 1, true},
+		{"group and min-member", gangPod("1", "ns", "g1", "3"), "g1", 3, true},
+		{"invalid min-member falls back to 1", gangPod("1", "ns", "g1", "not-a-number"), "g1", 1, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			name, minMember, ok := PodGang(tc.pod)
+			if ok != tc.wantOK || name != tc.wantGang || minMember != tc.wantMinMember {
+				t.Errorf("PodGang() = (%q, %d, %v), want (%q, %d, %v)", name, minMember, ok, tc.wantGang, tc.wantMinMember, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestGangCacheAssumePending(t *testing.T) {
+	c := NewGangCache(time.Minute)
+	p1 := gangPod("1", "ns", "g1", "2")
+	p2 := gangPod("2", "ns", "g1", "2")
+
+	ready, assumed := c.AssumePending(p1, "node-a")
+	if ready {
+		t.Fatalf("gang should not be ready with only 1/2 members assumed")
+	}
+	if len(assumed) != 1 {
+		t.Fatalf("expected 1 assumed pod, got %d", len(assumed))
+	}
+
+	ready, assumed = c.AssumePending(p2, "node-b")
+	if !ready {
+		t.Fatalf("gang should be ready once min-member is reached")
+	}
+	if len(assumed) != 2 {
+		t.Fatalf("expected 2 assumed pods, got %d", len(assumed))
+	}
+}
+
+func TestGangCacheTimeoutStartsNewCycle(t *testing.T) {
+	c := NewGangCache(0) // always expired
+	p1 := gangPod("1", "ns", "g1", "2")
+	p2 := gangPod("2", "ns", "g1", "2")
+
+	c.AssumePending(p1, "node-a")
+	_, assumed := c.AssumePending(p2, "node-b")
+	if _, ok := assumed[p1.UID]; ok {
+		t.Fatalf("expired attempt should not carry p1 into the new cycle")
+	}
+}
+
+func TestGangCacheAbandon(t *testing.T) {
+	c := NewGangCache(time.Minute)
+	p1 := gangPod("1", "ns", "g1", "3")
+	c.AssumePending(p1, "node-a")
+
+	rolledBack := c.Abandon("ns", "g1")
+	if len(rolledBack) != 1 {
+		t.Fatalf("expected 1 rolled-back pod, got %d", len(rolledBack))
+	}
+	if _, ok := c.attempts[gangKey{"ns", "g1"}]; ok {
+		t.Fatalf("abandoned gang should no longer be tracked")
+	}
+}
+
+func TestSelectGangVictims(t *testing.T) {
+	gang := []*v1.Pod{gangPod("1", "ns", "g1", "2"), gangPod("2", "ns", "g1", "2")}
+	candidates := []NodeVictims{
+		{NodeName: "big", Victims: []*v1.Pod{{}, {}, {}}},
+		{NodeName: "small", Victims: []*v1.Pod{{}}},
+		{NodeName: "medium", Victims: []*v1.Pod{{}, {}}},
+	}
+
+	selected := SelectGangVictims(gang, candidates)
+	if len(selected) != 2 {
+		t.Fatalf("expected 2 node victim sets selected, got %d", len(selected))
+	}
+	if selected[0].NodeName != "small" || selected[1].NodeName != "medium" {
+		t.Errorf("expected the two lowest-victim-count nodes (small, medium) first, got %v", selected)
+	}
+
+	if got := SelectGangVictims(make([]*v1.Pod, 4), candidates); got != nil {
+		t.Errorf("expected nil when candidates cannot cover the whole gang, got %v", got)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// ResourceDemand is the amount of a single resource (e.g. cpu millicores,
+// memory bytes) that must be freed for a preemptor to fit on a node.
+type ResourceDemand int64
+
+// resourceCost returns how much of resourceName a pod's requests would free
+// if the pod were removed.
+func resourceCost(pod *v1.Pod, resourceName v1.ResourceName) ResourceDemand {
+	var total int64
+	for _, c := range pod.Spec.Containers {
+		if q, ok := c.Resources.Requests[resourceName]; ok {
+			total += q.MilliValue()
+		}
+	}
+	return ResourceDemand(total)
+}
+
+// subset is one reachable combination of candidate indices, along with its
+// combined cost.
+type subset struct {
+	cost    ResourceDemand
+	indices []int
+}
+
+// betterSubset reports whether a ranks ahead of b as a victim set: fewer
+// pods first, then lower total priority, then latest StartTime among its
+// pods (i.e. prefer disturbing whatever was scheduled most recently).
+// Ranking on these keys is what makes one large low-priority pod beat
+// several medium-priority pods of equal aggregate size: the larger pod
+// reaches the required cost in fewer items.
+func betterSubset(a, b subset, pods []*v1.Pod) bool {
+	if len(a.indices) != len(b.indices) {
+		return len(a.indices) < len(b.indices)
+	}
+	aPriority, aLatest := subsetRank(a, pods)
+	bPriority, bLatest := subsetRank(b, pods)
+	if aPriority != bPriority {
+		return aPriority < bPriority
+	}
+	return aLatest.After(bLatest)
+}
+
+func subsetRank(s subset, pods []*v1.Pod) (totalPriority int64, latestStart time.Time) {
+	for _, idx := range s.indices {
+		pod := pods[idx]
+		if pod.Spec.Priority != nil {
+			totalPriority += int64(*pod.Spec.Priority)
+		}
+		if pod.Status.StartTime != nil && pod.Status.StartTime.Time.After(latestStart) {
+			latestStart = pod.Status.StartTime.Time
+		}
+	}
+	return totalPriority, latestStart
+}
+
+// SelectMinCostVictims solves, for a single node, the smallest-set-of-
+// victims-that-frees-at-least-required problem as a bounded 0/1 knapsack
+// over candidates sorted by priority ascending: it enumerates every
+// reachable subset cost exactly once (candidate victim lists per node are
+// small, since they are already filtered to pods at or below the
+// preemptor's priority), keeping only the best subset per reachable cost,
+// then returns the best subset whose cost meets or exceeds required. Ties
+// are broken by (pod count, total priority, latest StartTime), which in
+// practice favors preempting one large low-priority pod over several
+// medium-priority pods of equal aggregate size. It returns nil if no subset
+// of candidates frees enough room.
+//
+// SelectMinCostVictims is a standalone building block: nothing in this
+// tree yet calls it from the real per-node victim selection path, so
+// preemption does not actually minimize the victim set for any cluster
+// until one is wired in.
+func SelectMinCostVictims(candidates []*v1.Pod, resourceName v1.ResourceName, required ResourceDemand) []*v1.Pod {
+	if required <= 0 || len(candidates) == 0 {
+		return nil
+	}
+
+	sorted := make([]*v1.Pod, len(candidates))
+	copy(sorted, candidates)
+	sort.Slice(sorted, func(i, j int) bool { return podRank(sorted[i]) < podRank(sorted[j]) })
+
+	costs := make([]ResourceDemand, len(sorted))
+	for i, pod := range sorted {
+		costs[i] = resourceCost(pod, resourceName)
+	}
+
+	reachable := map[ResourceDemand]subset{0: {0, nil}}
+	for i, cost := range costs {
+		if cost <= 0 {
+			continue
+		}
+		additions := make(map[ResourceDemand]subset, len(reachable))
+		for c, s := range reachable {
+			newIndices := make([]int, len(s.indices), len(s.indices)+1)
+			copy(newIndices, s.indices)
+			newIndices = append(newIndices, i)
+			candidate := subset{cost: c + cost, indices: newIndices}
+
+			if existing, ok := reachable[candidate.cost]; !ok || betterSubset(candidate, existing, sorted) {
+				if existing, ok := additions[candidate.cost]; !ok || betterSubset(candidate, existing, sorted) {
+					additions[candidate.cost] = candidate
+				}
+			}
+		}
+		for c, s := range additions {
+			reachable[c] = s
+		}
+	}
+
+	var best *subset
+	for c, s := range reachable {
+		if c < required {
+			continue
+		}
+		if best == nil || betterSubset(s, *best, sorted) {
+			sCopy := s
+			best = &sCopy
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	victims := make([]*v1.Pod, 0, len(best.indices))
+	for _, idx := range best.indices {
+		victims = append(victims, sorted[idx])
+	}
+	return victims
+}
+
+// podRank orders candidates ascending by priority (lowest priority first,
+// i.e. most preferred to preempt).
+func podRank(pod *v1.Pod) int64 {
+	if pod.Spec.Priority != nil {
+		return int64(*pod.Spec.Priority)
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func cpuPod(name string, priority int32, milliCPU int64) *v1.Pod {
+	p := priority
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1.PodSpec{
+			Priority: &p,
+			Containers: []v1.Container{{
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: *resource.NewMilliQuantity(milliCPU, resource.DecimalSI)},
+				},
+			}},
+		},
+	}
+}
+
+func TestSelectMinCostVictimsPrefersOneBigPodOverManySmall(t *testing.T) {
+	big := cpuPod("big", 10, 400)
+	small1 := cpuPod("small1", 20, 200)
+	small2 := cpuPod("small2", 20, 200)
+
+	victims := SelectMinCostVictims([]*v1.Pod{big, small1, small2}, v1.ResourceCPU, 400)
+	if len(victims) != 1 || victims[0] != big {
+		t.Fatalf("expected the single large low-priority pod to be preferred, got %v", victims)
+	}
+}
+
+func TestSelectMinCostVictimsReturnsNilWhenInsufficient(t *testing.T) {
+	a := cpuPod("a", 10, 100)
+	victims := SelectMinCostVictims([]*v1.Pod{a}, v1.ResourceCPU, 1000)
+	if victims != nil {
+		t.Fatalf("expected nil when no subset can free enough room, got %v", victims)
+	}
+}
+
+func TestSelectMinCostVictimsCombinesWhenNoSingleCandidateSuffices(t *testing.T) {
+	a := cpuPod("a", 10, 100)
+	b := cpuPod("b", 10, 100)
+	c := cpuPod("c", 10, 100)
+
+	victims := SelectMinCostVictims([]*v1.Pod{a, b, c}, v1.ResourceCPU, 250)
+	var total int64
+	for _, v := range victims {
+		total += int64(resourceCost(v, v1.ResourceCPU))
+	}
+	if total < 250 {
+		t.Fatalf("expected the selected victims to free at least the required amount, got %d", total)
+	}
+	if len(victims) != 3 {
+		t.Fatalf("expected all 3 pods to be required to reach 250, got %d", len(victims))
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+)
+
+// PreemptionBlockedByPDB is set on a preemptor pod's status conditions when
+// every feasible victim set for it would violate a PodDisruptionBudget, so
+// users have somewhere to look for why preemption appears stuck.
+const PreemptionBlockedByPDB v1.PodConditionType = "PreemptionBlockedByPDB"
+
+// victimSet is one candidate set of pods preemption could remove to make
+// room for a preemptor, together with how many PodDisruptionBudgets it
+// would violate.
+type victimSet struct {
+	pods          []*v1.Pod
+	pdbViolations int
+}
+
+// totalPriority sums the priority of every pod in the set; used as the
+// second ranking key after PDB violations.
+func (v victimSet) totalPriority() int64 {
+	var total int64
+	for _, pod := range v.pods {
+		if pod.Spec.Priority != nil {
+			total += int64(*pod.Spec.Priority)
+		}
+	}
+	return total
+}
+
+// countPDBViolations returns how many of pods' removals would violate a
+// PodDisruptionBudget that currently has zero DisruptionsAllowed.
+func countPDBViolations(pods []*v1.Pod, pdbs []*policy.PodDisruptionBudget) int {
+	violations := 0
+	for _, pod := range pods {
+		for _, pdb := range pdbs {
+			if pdb.Namespace != pod.Namespace {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil || !selector.Matches(labelsOf(pod)) {
+				continue
+			}
+			if pdb.Status.DisruptionsAllowed <= 0 {
+				violations++
+			}
+			break
+		}
+	}
+	return violations
+}
+
+// labelsOf adapts a pod's labels to labels.Labels without importing the
+// labels package twice under two names at every call site.
+func labelsOf(pod *v1.Pod) podLabels {
+	return podLabels(pod.Labels)
+}
+
+type podLabels map[string]string
+
+func (p podLabels) Has(key string) bool   { _, ok := p[key]; return ok }
+func (p podLabels) Get(key string) string { return p[key] }
+
+// SelectVictimsPDBAware ranks candidate victim sets first by how few
+// PodDisruptionBudgets they would violate, then by total priority, then by
+// pod count, and returns the best one. It always returns the lowest-ranked
+// set rather than refusing to preempt, since the caller is only asked to
+// choose among sets that already free enough room; PDB-violation is a
+// preference, not a hard constraint, and callers surface
+// PreemptionBlockedByPDB separately when every set available violates one.
+//
+// SelectVictimsPDBAware and EvictVictim are standalone building blocks:
+// nothing in this tree yet calls them from the real victim-removal path, so
+// preemption does not actually go through the eviction subresource for any
+// cluster until one is wired in.
+func SelectVictimsPDBAware(candidates [][]*v1.Pod, pdbs []*policy.PodDisruptionBudget) []*v1.Pod {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sets := make([]victimSet, 0, len(candidates))
+	for _, pods := range candidates {
+		sets = append(sets, victimSet{pods: pods, pdbViolations: countPDBViolations(pods, pdbs)})
+	}
+
+	sort.Slice(sets, func(i, j int) bool {
+		if sets[i].pdbViolations != sets[j].pdbViolations {
+			return sets[i].pdbViolations < sets[j].pdbViolations
+		}
+		if sets[i].totalPriority() != sets[j].totalPriority() {
+			return sets[i].totalPriority() < sets[j].totalPriority()
+		}
+		return len(sets[i].pods) < len(sets[j].pods)
+	})
+
+	return sets[0].pods
+}
+
+// AllViolatePDB reports whether every candidate victim set would violate a
+// PodDisruptionBudget, i.e. there is no PDB-safe way to make room.
+func AllViolatePDB(candidates [][]*v1.Pod, pdbs []*policy.PodDisruptionBudget) bool {
+	if len(candidates) == 0 {
+		return false
+	}
+	for _, pods := range candidates {
+		if countPDBViolations(pods, pdbs) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// evictionBackoff bounds retries against the eviction subresource when the
+// API server is shedding load with 429 TooManyRequests, e.g. because a PDB
+// is already at its DisruptionsAllowed floor and other evictions are
+// contending for it.
+var evictionBackoff = wait.Backoff{
+	Duration: 100 * time.Millisecond,
+	Factor:   2,
+	Steps:    5,
+}
+
+// EvictVictim removes a preemption victim through the eviction subresource,
+// instead of a raw Delete, so a PodDisruptionBudget's DisruptionsAllowed is
+// correctly decremented and observers see an Evicted event rather than an
+// unexplained deletion. It retries with backoff on 429 TooManyRequests,
+// which the eviction API returns while a PDB has no budget left.
+func EvictVictim(cs clientset.Interface, pod *v1.Pod) error {
+	eviction := &policy.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+
+	err := retry.OnError(evictionBackoff, errors.IsTooManyRequests, func() error {
+		return cs.PolicyV1beta1().Evictions(pod.Namespace).Evict(eviction)
+	})
+	if err != nil {
+		return fmt.Errorf("evicting preemption victim %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+	klog.V(2).Infof("evicted preemption victim %s/%s", pod.Namespace, pod.Name)
+	return nil
+}
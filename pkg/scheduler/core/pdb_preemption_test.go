@@ -0,0 +1,78 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func priorityPod(name string, priority int32, labels map[string]string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: labels},
+		Spec:       v1.PodSpec{Priority: &priority},
+	}
+}
+
+func guardedPDB(disruptionsAllowed int32, labels map[string]string) *policy.PodDisruptionBudget {
+	return &policy.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns"},
+		Spec:       policy.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		Status:     policy.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+	}
+}
+
+func TestSelectVictimsPDBAware(t *testing.T) {
+	guarded := priorityPod("guarded", 10, map[string]string{"app": "guarded"})
+	free := priorityPod("free", 10, map[string]string{"app": "free"})
+	pdbs := []*policy.PodDisruptionBudget{guardedPDB(0, map[string]string{"app": "guarded"})}
+
+	candidates := [][]*v1.Pod{{guarded}, {free}}
+	selected := SelectVictimsPDBAware(candidates, pdbs)
+	if len(selected) != 1 || selected[0] != free {
+		t.Fatalf("expected the PDB-safe victim set to be preferred, got %v", selected)
+	}
+}
+
+func TestSelectVictimsPDBAwareFallsBackWhenAllViolate(t *testing.T) {
+	a := priorityPod("a", 10, map[string]string{"app": "guarded"})
+	b := priorityPod("b", 5, map[string]string{"app": "guarded"})
+	pdbs := []*policy.PodDisruptionBudget{guardedPDB(0, map[string]string{"app": "guarded"})}
+
+	candidates := [][]*v1.Pod{{a}, {b}}
+	selected := SelectVictimsPDBAware(candidates, pdbs)
+	if len(selected) != 1 || selected[0] != b {
+		t.Fatalf("expected the lower-priority set when all violate PDBs, got %v", selected)
+	}
+
+	if !AllViolatePDB(candidates, pdbs) {
+		t.Errorf("expected AllViolatePDB to be true when every candidate set violates a PDB")
+	}
+}
+
+func TestAllViolatePDBFalseWhenOneSafe(t *testing.T) {
+	guarded := priorityPod("guarded", 10, map[string]string{"app": "guarded"})
+	free := priorityPod("free", 10, map[string]string{"app": "free"})
+	pdbs := []*policy.PodDisruptionBudget{guardedPDB(0, map[string]string{"app": "guarded"})}
+
+	if AllViolatePDB([][]*v1.Pod{{guarded}, {free}}, pdbs) {
+		t.Errorf("expected AllViolatePDB to be false when a PDB-safe set exists")
+	}
+}
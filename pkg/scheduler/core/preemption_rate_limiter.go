@@ -0,0 +1,157 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// PreemptionRateLimiterConfiguration holds the maxPreemptionsPerMinute and
+// maxConcurrentInFlightPreemptions limits that cap how aggressively the
+// scheduler tears down victims. KubeSchedulerConfiguration has no
+// corresponding fields.
+type PreemptionRateLimiterConfiguration struct {
+	// MaxPreemptionsPerMinute bounds, per node and for the whole cluster,
+	// how many victim pods may be nominated for preemption in a sliding
+	// one-minute window. Zero means unlimited.
+	MaxPreemptionsPerMinute int32
+	// MaxConcurrentInFlightPreemptions bounds how many victims may be
+	// nominated and not yet resolved (bound, deleted, or rolled back) at
+	// once, cluster-wide. Zero means unlimited.
+	MaxConcurrentInFlightPreemptions int32
+}
+
+// window is a sliding one-minute log of nomination timestamps, used to
+// enforce a per-minute rate independent of when in the minute it started.
+type window struct {
+	times []time.Time
+}
+
+func (w *window) record(now time.Time) {
+	w.times = append(w.times, now)
+}
+
+// countSince drops entries older than cutoff and returns how many remain.
+func (w *window) countSince(cutoff time.Time) int {
+	i := 0
+	for i < len(w.times) && w.times[i].Before(cutoff) {
+		i++
+	}
+	w.times = w.times[i:]
+	return len(w.times)
+}
+
+// PreemptionRateLimiter caps how many victim pods the scheduler may
+// nominate for preemption per node and across the whole cluster in a
+// sliding window, and how many nominations may be outstanding at once. It
+// exists so a burst of high-priority pods drains a saturated cluster
+// gradually, rather than preempting every fitting victim in a single storm
+// before any of the preemptors can actually bind.
+//
+// PreemptionRateLimiter is a standalone building block: nothing in this
+// tree yet consults it from a real nomination/Delete path or wires
+// PreemptionRateLimiterConfiguration into KubeSchedulerConfiguration, so
+// preemption is not actually rate-limited for any cluster until one is
+// wired in.
+type PreemptionRateLimiter struct {
+	cfg PreemptionRateLimiterConfiguration
+
+	mu          sync.Mutex
+	clusterWin  window
+	nodeWindows map[string]*window
+	inFlight    map[types.UID]string // victim UID -> node name
+}
+
+// NewPreemptionRateLimiter returns a PreemptionRateLimiter enforcing cfg. A
+// zero-value cfg imposes no limits.
+func NewPreemptionRateLimiter(cfg PreemptionRateLimiterConfiguration) *PreemptionRateLimiter {
+	return &PreemptionRateLimiter{
+		cfg:         cfg,
+		nodeWindows: make(map[string]*window),
+		inFlight:    make(map[types.UID]string),
+	}
+}
+
+// nowFunc is overridden in tests so the sliding window can be exercised
+// without real time passing.
+var nowFunc = time.Now
+
+// Allow reports whether victimUID on nodeName may be nominated for
+// preemption right now, given everything already nominated and not yet
+// resolved via Resolve. A victim still in flight from an earlier Allow call
+// is allowed again without a second charge against the rate; one passed
+// again after Resolve is charged as a new nomination. Only this limiter's
+// own in-flight set is checked; the nominated-pod cache is not consulted.
+func (l *PreemptionRateLimiter) Allow(victimUID types.UID, nodeName string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, already := l.inFlight[victimUID]; already {
+		// Already accounted for by a previous Allow call and not yet
+		// resolved, so a repeat nomination of the same victim is a no-op
+		// here rather than a second charge against the rate.
+		return true
+	}
+
+	if l.cfg.MaxConcurrentInFlightPreemptions > 0 && int32(len(l.inFlight)) >= l.cfg.MaxConcurrentInFlightPreemptions {
+		return false
+	}
+
+	if l.cfg.MaxPreemptionsPerMinute > 0 {
+		now := nowFunc()
+		cutoff := now.Add(-time.Minute)
+
+		if l.clusterWin.countSince(cutoff) >= int(l.cfg.MaxPreemptionsPerMinute) {
+			return false
+		}
+		nodeWin := l.nodeWindows[nodeName]
+		if nodeWin == nil {
+			nodeWin = &window{}
+			l.nodeWindows[nodeName] = nodeWin
+		}
+		if nodeWin.countSince(cutoff) >= int(l.cfg.MaxPreemptionsPerMinute) {
+			return false
+		}
+
+		l.clusterWin.record(now)
+		nodeWin.record(now)
+	}
+
+	l.inFlight[victimUID] = nodeName
+	return true
+}
+
+// Resolve marks victimUID's nomination as settled, whether it was bound,
+// deleted, or rolled back, freeing its slot against
+// MaxConcurrentInFlightPreemptions. It does not undo the rate-window charge,
+// since the nomination still happened within that window.
+func (l *PreemptionRateLimiter) Resolve(victimUID types.UID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.inFlight, victimUID)
+}
+
+// InFlight reports how many nominations are currently outstanding,
+// cluster-wide; exported for tests and metrics.
+func (l *PreemptionRateLimiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.inFlight)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func withFakeClock(t *testing.T, start time.Time) func(advance time.Duration) {
+	now := start
+	old := nowFunc
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(func() { nowFunc = old })
+	return func(advance time.Duration) { now = now.Add(advance) }
+}
+
+func TestPreemptionRateLimiterPerMinuteCap(t *testing.T) {
+	advance := withFakeClock(t, time.Unix(0, 0))
+	l := NewPreemptionRateLimiter(PreemptionRateLimiterConfiguration{MaxPreemptionsPerMinute: 2})
+
+	if !l.Allow(types.UID("a"), "node1") || !l.Allow(types.UID("b"), "node1") {
+		t.Fatalf("expected the first two nominations within the cap to be allowed")
+	}
+	if l.Allow(types.UID("c"), "node1") {
+		t.Fatalf("expected a third nomination within the same minute to be denied")
+	}
+
+	advance(time.Minute + time.Second)
+	if !l.Allow(types.UID("c"), "node1") {
+		t.Fatalf("expected the cap to reset once the window slides past the earlier nominations")
+	}
+}
+
+func TestPreemptionRateLimiterClusterWindowSpansNodes(t *testing.T) {
+	withFakeClock(t, time.Unix(0, 0))
+	l := NewPreemptionRateLimiter(PreemptionRateLimiterConfiguration{MaxPreemptionsPerMinute: 1})
+
+	if !l.Allow(types.UID("a"), "node1") {
+		t.Fatalf("expected the first nomination on node1 to be allowed")
+	}
+	if l.Allow(types.UID("b"), "node1") {
+		t.Fatalf("expected a second nomination on node1 within the cap to be denied")
+	}
+	if l.Allow(types.UID("c"), "node2") {
+		t.Fatalf("expected a nomination on node2 to be denied too, since the cluster-wide window already holds node1's")
+	}
+}
+
+func TestPreemptionRateLimiterMaxInFlight(t *testing.T) {
+	withFakeClock(t, time.Unix(0, 0))
+	l := NewPreemptionRateLimiter(PreemptionRateLimiterConfiguration{MaxConcurrentInFlightPreemptions: 1})
+
+	if !l.Allow(types.UID("a"), "node1") {
+		t.Fatalf("expected the first in-flight nomination to be allowed")
+	}
+	if l.Allow(types.UID("b"), "node1") {
+		t.Fatalf("expected a second concurrent nomination to be denied while the first is unresolved")
+	}
+
+	l.Resolve(types.UID("a"))
+	if !l.Allow(types.UID("b"), "node1") {
+		t.Fatalf("expected resolving the first nomination to free a slot for the second")
+	}
+}
+
+func TestPreemptionRateLimiterNoDoubleCountingSameVictim(t *testing.T) {
+	withFakeClock(t, time.Unix(0, 0))
+	l := NewPreemptionRateLimiter(PreemptionRateLimiterConfiguration{MaxPreemptionsPerMinute: 1})
+
+	if !l.Allow(types.UID("a"), "node1") {
+		t.Fatalf("expected the first nomination to be allowed")
+	}
+	if !l.Allow(types.UID("a"), "node1") {
+		t.Fatalf("expected re-nominating an already-accounted-for victim to be a no-op, not a second charge")
+	}
+	if l.InFlight() != 1 {
+		t.Fatalf("expected exactly 1 in-flight nomination, got %d", l.InFlight())
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+)
+
+// PreemptionVictimStuck is emitted on a victim pod when it failed to
+// terminate within its grace period (plus slack) after the scheduler issued
+// Delete, and had to be force-deleted to unblock the preemptor waiting on
+// it.
+const PreemptionVictimStuck = "PreemptionVictimStuck"
+
+// victimDeadlineSlack is added on top of a victim's own
+// TerminationGracePeriodSeconds before the reconciler force-deletes it, to
+// absorb normal scheduling/propagation delay rather than racing the
+// kubelet's own grace period handling.
+const victimDeadlineSlack = 30 * time.Second
+
+// victimRecord tracks one pod the scheduler has nominated for deletion to
+// make room for a preemptor.
+type victimRecord struct {
+	victimUID    types.UID
+	namespace    string
+	name         string
+	deadline     time.Time
+	preemptorUID types.UID
+}
+
+// VictimReconciler tracks victims the scheduler has told the API server to
+// delete during preemption, and force-terminates any that do not go away
+// within their grace period. Deletion and scheduling accounting can
+// otherwise disagree: a victim stuck in a non-terminal phase (e.g. behind a
+// slow preStop hook, or a kubelet that never observed the delete) leaves its
+// preemptor unschedulable forever with no automatic recovery.
+//
+// VictimReconciler is a standalone building block: nothing in this tree
+// yet constructs one outside its own test or drives Reconcile from a
+// scheduler run loop, so stuck victims are not actually force-terminated
+// for any cluster until one is wired in.
+type VictimReconciler struct {
+	client   clientset.Interface
+	recorder record.EventRecorder
+
+	// onReanimated is called when a tracked victim unexpectedly transitions
+	// to Running or Succeeded after Delete was issued for it. A caller can
+	// wire this to re-attempt preemption for preemptorUID rather than
+	// leaving it unschedulable on the strength of a Delete call that never
+	// actually freed the resources it was counted on to free.
+	onReanimated func(preemptorUID types.UID, victim *v1.Pod)
+
+	mu      sync.Mutex
+	victims map[types.UID]*victimRecord // keyed by victimUID
+}
+
+// NewVictimReconciler returns a VictimReconciler that force-deletes victims
+// through client, records events via recorder, and invokes onReanimated
+// (which may be nil) when a victim comes back to life instead of
+// terminating.
+func NewVictimReconciler(client clientset.Interface, recorder record.EventRecorder, onReanimated func(preemptorUID types.UID, victim *v1.Pod)) *VictimReconciler {
+	return &VictimReconciler{
+		client:       client,
+		recorder:     recorder,
+		onReanimated: onReanimated,
+		victims:      make(map[types.UID]*victimRecord),
+	}
+}
+
+// RecordDelete registers that victim was just handed a Delete call on behalf
+// of preemptor, and should be force-terminated if it has not gone away by
+// victim's TerminationGracePeriodSeconds plus slack.
+func (r *VictimReconciler) RecordDelete(victim *v1.Pod, preemptorUID types.UID) {
+	grace := int64(corev1DefaultGracePeriodSeconds)
+	if victim.Spec.TerminationGracePeriodSeconds != nil {
+		grace = *victim.Spec.TerminationGracePeriodSeconds
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.victims[victim.UID] = &victimRecord{
+		victimUID:    victim.UID,
+		namespace:    victim.Namespace,
+		name:         victim.Name,
+		deadline:     time.Now().Add(time.Duration(grace)*time.Second + victimDeadlineSlack),
+		preemptorUID: preemptorUID,
+	}
+}
+
+// corev1DefaultGracePeriodSeconds mirrors the API server's default pod grace
+// period, used when a victim's spec does not set one explicitly.
+const corev1DefaultGracePeriodSeconds = 30
+
+// CancelForPreemptor drops every outstanding force-delete this reconciler
+// was tracking on behalf of preemptorUID, e.g. because the preemptor itself
+// was deleted while its victims were still terminating and force-deleting
+// them would no longer help anyone.
+func (r *VictimReconciler) CancelForPreemptor(preemptorUID types.UID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for uid, rec := range r.victims {
+		if rec.preemptorUID == preemptorUID {
+			delete(r.victims, uid)
+		}
+	}
+}
+
+// Reconcile sweeps the tracked victims that are past their deadline: it
+// force-deletes any that are still present, and stops tracking any that
+// have already disappeared or unexpectedly come back to life. Victims not
+// yet past their deadline are not checked.
+func (r *VictimReconciler) Reconcile() {
+	r.mu.Lock()
+	due := make([]*victimRecord, 0)
+	now := time.Now()
+	for _, rec := range r.victims {
+		if now.After(rec.deadline) {
+			due = append(due, rec)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, rec := range due {
+		r.forceDelete(rec)
+	}
+}
+
+// forceDelete removes a victim with zero grace period and emits
+// PreemptionVictimStuck, then stops tracking it regardless of outcome: a
+// NotFound here just means it finally terminated on its own in the window
+// between the sweep and the delete call.
+func (r *VictimReconciler) forceDelete(rec *victimRecord) {
+	pod, err := r.client.CoreV1().Pods(rec.namespace).Get(rec.name, metav1.GetOptions{})
+	if err == nil && pod.UID == rec.victimUID {
+		if isUnexpectedlyAlive(pod) {
+			klog.Warningf("preemption victim %s/%s came back to %v after Delete; not force-deleting, but no longer counting it as preempted", rec.namespace, rec.name, pod.Status.Phase)
+			if r.onReanimated != nil {
+				r.onReanimated(rec.preemptorUID, pod)
+			}
+			r.forget(rec.victimUID)
+			return
+		}
+
+		zero := int64(0)
+		deleteErr := r.client.CoreV1().Pods(rec.namespace).Delete(rec.name, &metav1.DeleteOptions{
+			GracePeriodSeconds: &zero,
+			Preconditions:      &metav1.Preconditions{UID: &rec.victimUID},
+		})
+		if deleteErr != nil {
+			klog.Errorf("force-deleting stuck preemption victim %s/%s: %v", rec.namespace, rec.name, deleteErr)
+		} else {
+			klog.Warningf("force-deleted preemption victim %s/%s: still present %v after its grace period", rec.namespace, rec.name, time.Since(rec.deadline))
+			if r.recorder != nil {
+				r.recorder.Eventf(pod, v1.EventTypeWarning, PreemptionVictimStuck, "Preemption victim did not terminate within its grace period and slack; force-deleted")
+			}
+		}
+	}
+
+	r.forget(rec.victimUID)
+}
+
+// isUnexpectedlyAlive reports whether a pod already told to terminate has
+// instead transitioned to Running or Succeeded, which preemption accounting
+// must treat as "this victim's removal did not happen" rather than silently
+// trusting the earlier Delete call.
+func isUnexpectedlyAlive(pod *v1.Pod) bool {
+	return pod.DeletionTimestamp == nil && (pod.Status.Phase == v1.PodRunning || pod.Status.Phase == v1.PodSucceeded)
+}
+
+func (r *VictimReconciler) forget(victimUID types.UID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.victims, victimUID)
+}
+
+// IsTracked reports whether victimUID is still being waited on by the
+// reconciler; exported for tests and for the scheduler to decide whether a
+// still-pending preemptor should be re-attempted rather than left
+// unschedulable.
+func (r *VictimReconciler) IsTracked(victimUID types.UID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.victims[victimUID]
+	return ok
+}
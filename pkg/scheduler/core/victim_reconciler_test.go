@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestVictimReconcilerForceDeletesPastDeadline(t *testing.T) {
+	// A victim stuck terminating after its Delete still carries the
+	// DeletionTimestamp that Delete set.
+	deletedAt := metav1.Now()
+	victim := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "victim", Namespace: "ns", UID: types.UID("victim-uid"), DeletionTimestamp: &deletedAt},
+		Spec:       v1.PodSpec{TerminationGracePeriodSeconds: int64Ptr(0)},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	client := fake.NewSimpleClientset(victim)
+	r := NewVictimReconciler(client, nil, nil)
+
+	r.RecordDelete(victim, types.UID("preemptor-uid"))
+	// Force the deadline into the past without sleeping past victimDeadlineSlack.
+	r.mu.Lock()
+	r.victims[victim.UID].deadline = time.Now().Add(-time.Second)
+	r.mu.Unlock()
+
+	r.Reconcile()
+
+	if r.IsTracked(victim.UID) {
+		t.Fatalf("expected victim to no longer be tracked after reconciling past its deadline")
+	}
+	if _, err := client.CoreV1().Pods("ns").Get("victim", metav1.GetOptions{}); err == nil {
+		t.Fatalf("expected the victim to have been force-deleted")
+	}
+}
+
+func TestVictimReconcilerReanimationCallback(t *testing.T) {
+	victim := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "victim", Namespace: "ns", UID: types.UID("victim-uid")},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	client := fake.NewSimpleClientset(victim)
+
+	var calledFor types.UID
+	r := NewVictimReconciler(client, nil, func(preemptorUID types.UID, pod *v1.Pod) {
+		calledFor = preemptorUID
+	})
+
+	r.RecordDelete(victim, types.UID("preemptor-uid"))
+	r.mu.Lock()
+	r.victims[victim.UID].deadline = time.Now().Add(-time.Second)
+	r.mu.Unlock()
+
+	r.Reconcile()
+
+	if calledFor != types.UID("preemptor-uid") {
+		t.Fatalf("expected onReanimated to fire for the waiting preemptor, got %q", calledFor)
+	}
+	if r.IsTracked(victim.UID) {
+		t.Fatalf("expected the reanimated victim to stop being tracked")
+	}
+}
+
+func TestVictimReconcilerCancelForPreemptor(t *testing.T) {
+	victim := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "victim", Namespace: "ns", UID: types.UID("victim-uid")}}
+	client := fake.NewSimpleClientset(victim)
+	r := NewVictimReconciler(client, nil, nil)
+
+	r.RecordDelete(victim, types.UID("preemptor-uid"))
+	r.CancelForPreemptor(types.UID("preemptor-uid"))
+
+	if r.IsTracked(victim.UID) {
+		t.Fatalf("expected cancelling a preemptor's outstanding force-deletes to stop tracking its victim")
+	}
+}
+
+func int64Ptr(v int64) *int64 { return &v }
